@@ -0,0 +1,170 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy controls what SetAsync does when its buffer is full.
+type DropPolicy int
+
+// Supported drop policies. Block is the zero value, matching the
+// synchronous behavior of a Logger that hasn't called SetAsync.
+const (
+	// Block makes the caller wait for room in the buffer.
+	Block DropPolicy = iota
+	// DropOldest discards the oldest buffered record to make room.
+	DropOldest
+	// DropNewest discards the record that didn't fit.
+	DropNewest
+)
+
+// asyncState is the background worker behind Logger.SetAsync.
+type asyncState struct {
+	ch    chan Record
+	flush chan chan struct{}
+	stop  chan struct{}
+	wg    sync.WaitGroup
+	drops uint64 // atomic
+}
+
+// SetAsync starts a background goroutine that drains a buffered channel of
+// records to the configured sinks, so callers no longer block on sink I/O.
+// FatalLevel records are always written synchronously, so the program
+// doesn't exit before they land. Calling SetAsync again replaces the
+// worker, first draining and stopping the old one.
+func (l *Logger) SetAsync(bufSize int) {
+	l.Lock()
+	old := l.async
+	a := &asyncState{
+		ch:    make(chan Record, bufSize),
+		flush: make(chan chan struct{}),
+		stop:  make(chan struct{}),
+	}
+	l.async = a
+	l.Unlock()
+
+	if old != nil {
+		l.stopAsync(old)
+	}
+
+	a.wg.Add(1)
+	go l.runAsync(a)
+}
+
+// SetAsync is a convenient wrapper to Default.SetAsync.
+func SetAsync(bufSize int) {
+	Default.SetAsync(bufSize)
+}
+
+// SetDropPolicy changes what a running (or future) async worker does when
+// its buffer is full.
+func (l *Logger) SetDropPolicy(p DropPolicy) {
+	l.Lock()
+	l.dropPolicy = p
+	l.Unlock()
+}
+
+// SetDropPolicy is a convenient wrapper to Default.SetDropPolicy.
+func SetDropPolicy(p DropPolicy) {
+	Default.SetDropPolicy(p)
+}
+
+func (l *Logger) runAsync(a *asyncState) {
+	defer a.wg.Done()
+	for {
+		select {
+		case rec := <-a.ch:
+			l.writeSinks(rec)
+		case done := <-a.flush:
+			for {
+				select {
+				case rec := <-a.ch:
+					l.writeSinks(rec)
+					continue
+				default:
+				}
+				break
+			}
+			close(done)
+		case <-a.stop:
+			for {
+				select {
+				case rec := <-a.ch:
+					l.writeSinks(rec)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// enqueue buffers rec, applying policy if the buffer is full.
+func (l *Logger) enqueue(a *asyncState, rec Record) {
+	select {
+	case a.ch <- rec:
+		return
+	default:
+	}
+
+	l.Lock()
+	policy := l.dropPolicy
+	l.Unlock()
+
+	switch policy {
+	case DropNewest:
+		atomic.AddUint64(&a.drops, 1)
+	case DropOldest:
+		select {
+		case <-a.ch:
+			atomic.AddUint64(&a.drops, 1)
+		default:
+		}
+		select {
+		case a.ch <- rec:
+		default:
+			atomic.AddUint64(&a.drops, 1)
+		}
+	default: // Block
+		a.ch <- rec
+	}
+}
+
+// Flush blocks until every record buffered so far has been written, and
+// returns the number of records dropped by the async buffer since it
+// started (0 if the Logger isn't in async mode).
+func (l *Logger) Flush() uint64 {
+	l.Lock()
+	a := l.async
+	l.Unlock()
+	if a == nil {
+		return 0
+	}
+
+	done := make(chan struct{})
+	a.flush <- done
+	<-done
+	return atomic.LoadUint64(&a.drops)
+}
+
+// Flush is a convenient wrapper to Default.Flush.
+func Flush() uint64 {
+	return Default.Flush()
+}
+
+func (l *Logger) stopAsync(a *asyncState) {
+	close(a.stop)
+	a.wg.Wait()
+}
+
+// writeSinks fans rec out to every sink synchronously.
+func (l *Logger) writeSinks(rec Record) {
+	l.Lock()
+	sinks := l.sinks
+	l.Unlock()
+
+	for _, s := range sinks {
+		s.Write(rec)
+	}
+}
@@ -0,0 +1,56 @@
+package log
+
+import "testing"
+
+func newTestAsyncState(bufSize int) *asyncState {
+	return &asyncState{
+		ch:    make(chan Record, bufSize),
+		flush: make(chan chan struct{}),
+		stop:  make(chan struct{}),
+	}
+}
+
+func TestEnqueueBlockDeliversInOrder(t *testing.T) {
+	l := &Logger{dropPolicy: Block}
+	a := newTestAsyncState(2)
+
+	l.enqueue(a, Record{Msg: "one"})
+	l.enqueue(a, Record{Msg: "two"})
+
+	if got := (<-a.ch).Msg; got != "one" {
+		t.Errorf("first dequeued record = %q, want %q", got, "one")
+	}
+	if got := (<-a.ch).Msg; got != "two" {
+		t.Errorf("second dequeued record = %q, want %q", got, "two")
+	}
+}
+
+func TestEnqueueDropNewest(t *testing.T) {
+	l := &Logger{dropPolicy: DropNewest}
+	a := newTestAsyncState(1)
+
+	l.enqueue(a, Record{Msg: "kept"})
+	l.enqueue(a, Record{Msg: "dropped"}) // buffer full, policy drops the new record
+
+	if got := (<-a.ch).Msg; got != "kept" {
+		t.Errorf("buffered record = %q, want %q", got, "kept")
+	}
+	if drops := a.drops; drops != 1 {
+		t.Errorf("drops = %d, want 1", drops)
+	}
+}
+
+func TestEnqueueDropOldest(t *testing.T) {
+	l := &Logger{dropPolicy: DropOldest}
+	a := newTestAsyncState(1)
+
+	l.enqueue(a, Record{Msg: "oldest"})
+	l.enqueue(a, Record{Msg: "newest"}) // buffer full, policy evicts the buffered record
+
+	if got := (<-a.ch).Msg; got != "newest" {
+		t.Errorf("buffered record = %q, want %q", got, "newest")
+	}
+	if drops := a.drops; drops != 1 {
+		t.Errorf("drops = %d, want 1 (the evicted record counts as dropped)", drops)
+	}
+}
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSinkRotate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.log")
+
+	sink, err := NewFileSink(path, 10, 2)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	fs := sink.(*fileSink)
+	defer fs.Close()
+
+	rec := Record{Msg: "x"} // letter()+" "+text()+"\n" is well over 10 bytes
+
+	for i := 0; i < 3; i++ {
+		if err := fs.Write(rec); err != nil {
+			t.Fatalf("Write #%d: %v", i, err)
+		}
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("current log file missing: %v", err)
+	}
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("expected rotated backup %s.1: %v", path, err)
+	}
+}
+
+func TestFileSinkRotatePrunesOldBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.log")
+
+	sink, err := NewFileSink(path, 10, 1)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	fs := sink.(*fileSink)
+	defer fs.Close()
+
+	rec := Record{Msg: "x"}
+	for i := 0; i < 4; i++ {
+		if err := fs.Write(rec); err != nil {
+			t.Fatalf("Write #%d: %v", i, err)
+		}
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("expected backup %s.1 to survive: %v", path, err)
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Errorf("expected no %s.2 with maxBackups=1, got err=%v", path, err)
+	}
+}
+
+func TestFileSinkRotateDisabledWithoutMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.log")
+
+	sink, err := NewFileSink(path, 0, 2)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	fs := sink.(*fileSink)
+	defer fs.Close()
+
+	for i := 0; i < 5; i++ {
+		if err := fs.Write(Record{Msg: "x"}); err != nil {
+			t.Fatalf("Write #%d: %v", i, err)
+		}
+	}
+
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Errorf("maxBytes=0 should disable rotation, but found %s.1 (err=%v)", path, err)
+	}
+}
@@ -0,0 +1,129 @@
+package log
+
+import (
+	"flag"
+	"strconv"
+	"sync"
+)
+
+// defaultStderrSink is the Sink Default starts with. RegisterFlags removes
+// it once -logfile or -logtosyslog configures a destination, unless
+// -alsologtostderr is also set, so that destination doesn't silently
+// duplicate onto stderr too.
+var defaultStderrSink = newStderrStreamSink()
+
+// registerState is the bookkeeping shared by the flags RegisterFlags wires
+// up. reconcileLocked decides whether Default should have a stderr sink
+// from the combined, order-independent state of -logfile/-logtosyslog and
+// -alsologtostderr, however the flags were given on the command line.
+type registerState struct {
+	mu         sync.Mutex
+	haveDest   bool // a -logfile or -logtosyslog destination is configured
+	alsoStderr bool
+	stderrOn   bool // whether Default currently carries a stderr sink
+	stderrSink Sink // the instance reconcileLocked last added, if stderrOn
+}
+
+func newRegisterState() *registerState {
+	return &registerState{stderrOn: true, stderrSink: defaultStderrSink}
+}
+
+func (s *registerState) markDestConfigured() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.haveDest = true
+	s.reconcileLocked()
+}
+
+func (s *registerState) setAlsoStderr(b bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alsoStderr = b
+	s.reconcileLocked()
+}
+
+// reconcileLocked must be called with s.mu held.
+func (s *registerState) reconcileLocked() {
+	want := s.alsoStderr || !s.haveDest
+	if want == s.stderrOn {
+		return
+	}
+	if want {
+		s.stderrSink = NewStderrSink()
+		Default.AddSink(s.stderrSink)
+	} else {
+		Default.RemoveSink(s.stderrSink)
+		s.stderrSink = nil
+	}
+	s.stderrOn = want
+}
+
+// boolFlag lets a flag.Func-style callback be set bare, e.g.
+// -alsologtostderr instead of -alsologtostderr=true.
+type boolFlag func(bool) error
+
+func (f boolFlag) String() string   { return "" }
+func (f boolFlag) IsBoolFlag() bool { return true }
+func (f boolFlag) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	return f(b)
+}
+
+// RegisterFlags wires flags controlling Default into fs: -v (verbosity),
+// -logvmodule (per-file verbosity overrides), -logfile (path, enables
+// timestamps), -logtosyslog (tag), -logtime, and -alsologtostderr. Call it
+// before flag.Parse(); Default is reconfigured as each flag is parsed, e.g.:
+//
+//	log.RegisterFlags(flag.CommandLine)
+//	flag.Parse()
+func RegisterFlags(fs *flag.FlagSet) {
+	state := newRegisterState()
+
+	fs.Func("v", "log verbosity level consulted by V() (and -logvmodule)", func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		Default.Lock()
+		Default.Verbosity = n
+		Default.Unlock()
+		return nil
+	})
+
+	fs.Func("logvmodule", "comma-separated pattern=level rules overriding -v for matching files", func(spec string) error {
+		return Default.SetVModule(spec)
+	})
+
+	fs.Func("logfile", "write logs to this file instead of stderr (implies -logtime)", func(path string) error {
+		if path == "" {
+			return nil
+		}
+		sink, err := NewFileSink(path, 0, 0)
+		if err != nil {
+			return err
+		}
+		Default.AddSink(sink)
+		Default.Lock()
+		Default.Time = true
+		Default.Unlock()
+		state.markDestConfigured()
+		return nil
+	})
+
+	registerSyslogFlag(fs, state)
+
+	fs.Var(boolFlag(func(b bool) error {
+		Default.Lock()
+		Default.Time = b
+		Default.Unlock()
+		return nil
+	}), "logtime", "include timestamps in stream/file sink output (default true; turn off for systemd)")
+
+	fs.Var(boolFlag(func(b bool) error {
+		state.setAlsoStderr(b)
+		return nil
+	}), "alsologtostderr", "also log to stderr in addition to -logfile/-logtosyslog")
+}
@@ -0,0 +1,21 @@
+//go:build windows || nacl || plan9
+// +build windows nacl plan9
+
+package log
+
+import (
+	"flag"
+	"fmt"
+)
+
+// registerSyslogFlag wires -logtosyslog on platforms without log/syslog: the
+// flag is accepted but rejects any non-empty value.
+func registerSyslogFlag(fs *flag.FlagSet, state *registerState) {
+	_ = state
+	fs.Func("logtosyslog", "log to syslog tagged with this value (unsupported on this platform)", func(tag string) error {
+		if tag == "" {
+			return nil
+		}
+		return fmt.Errorf("log: -logtosyslog is not supported on this platform")
+	})
+}
@@ -0,0 +1,26 @@
+//go:build !windows && !nacl && !plan9
+// +build !windows,!nacl,!plan9
+
+package log
+
+import (
+	"flag"
+	"log/syslog"
+)
+
+// registerSyslogFlag wires -logtosyslog into Default, dialing the local
+// syslog daemon tagged with the flag's value under syslog.LOG_USER.
+func registerSyslogFlag(fs *flag.FlagSet, state *registerState) {
+	fs.Func("logtosyslog", "log to syslog tagged with this value, in addition to any -logfile", func(tag string) error {
+		if tag == "" {
+			return nil
+		}
+		sink, err := NewSyslogSink(tag, syslog.LOG_USER)
+		if err != nil {
+			return err
+		}
+		Default.AddSink(sink)
+		state.markDestConfigured()
+		return nil
+	})
+}
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+)
+
+func withTestDefault(t *testing.T) {
+	orig := Default
+	Default = &Logger{
+		Level:   ErrorLevel,
+		Time:    true,
+		sinks:   []Sink{defaultStderrSink},
+		limiter: newRateLimiter(),
+	}
+	t.Cleanup(func() { Default = orig })
+}
+
+func TestRegisterFlagsAlsoStderrToggleRemovesAddedSink(t *testing.T) {
+	withTestDefault(t)
+	path := filepath.Join(t.TempDir(), "out.log")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs)
+
+	if err := fs.Parse([]string{"-logfile=" + path, "-alsologtostderr=true", "-alsologtostderr=false"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if got := len(Default.sinks); got != 1 {
+		t.Errorf("Default has %d sinks after -alsologtostderr=true then =false, want 1 (just -logfile's sink, the toggled-on stderr sink should have been removed)", got)
+	}
+}
+
+func TestRegisterFlagsAlsoStderrKeepsDestSinkOnToggleOff(t *testing.T) {
+	withTestDefault(t)
+	path := filepath.Join(t.TempDir(), "out.log")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs)
+
+	if err := fs.Parse([]string{"-logfile=" + path, "-alsologtostderr=true", "-alsologtostderr=false"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if _, ok := Default.sinks[0].(*fileSink); !ok {
+		t.Errorf("remaining sink = %T, want *fileSink", Default.sinks[0])
+	}
+}
+
+func TestRegisterFlagsVDoesNotRaiseLevel(t *testing.T) {
+	withTestDefault(t)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs)
+
+	if err := fs.Parse([]string{"-v=2"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if Default.Verbosity != 2 {
+		t.Errorf("Default.Verbosity = %d, want 2", Default.Verbosity)
+	}
+	if Default.Level != ErrorLevel {
+		t.Errorf("Default.Level = %v, want ErrorLevel (-v must not change the plain logging level)", Default.Level)
+	}
+}
@@ -0,0 +1,77 @@
+package log
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecordJSONNestsFieldsUnderKey(t *testing.T) {
+	rec := Record{
+		Level:  InfoLevel,
+		Time:   time.Now(),
+		File:   "main.go",
+		Line:   42,
+		Event:  "user logged in",
+		Fields: map[string]interface{}{"level": "admin", "user": "alice"},
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(rec.json(), &obj); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+
+	if obj["msg"] != "user logged in" {
+		t.Errorf("msg = %v, want %q", obj["msg"], "user logged in")
+	}
+	if obj["level"] != "info" {
+		t.Errorf("level = %v, want %q (the record's own level, not clobbered by the user field named level)", obj["level"], "info")
+	}
+	fields, ok := obj["fields"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("fields = %v (%T), want a nested object", obj["fields"], obj["fields"])
+	}
+	if fields["level"] != "admin" {
+		t.Errorf("fields.level = %v, want %q", fields["level"], "admin")
+	}
+}
+
+func TestRecordJSONPlainMsg(t *testing.T) {
+	rec := Record{Level: ErrorLevel, Time: time.Now(), File: "main.go", Line: 1, Msg: "boom"}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(rec.json(), &obj); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if obj["msg"] != "boom" {
+		t.Errorf("msg = %v, want %q", obj["msg"], "boom")
+	}
+	if _, ok := obj["fields"]; ok {
+		t.Error("fields should be absent for a plain printf-style record")
+	}
+}
+
+// TestSetFormatConcurrentWithInfof guards against the race between SetFormat
+// writing l.Format under lock and log/vlog/logw reading it: run with
+// -race, this must pass without the detector firing.
+func TestSetFormatConcurrentWithInfof(t *testing.T) {
+	l := &Logger{Level: DebugLevel, sinks: []Sink{&memSink{}}, limiter: newRateLimiter()}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			l.SetFormat(FormatJSON)
+			l.SetFormat(FormatText)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			l.Infof("hello %d", i)
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,43 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestJournaldSinkPriorityPrefix(t *testing.T) {
+	tests := []struct {
+		level  Level
+		prefix string
+	}{
+		{FatalLevel, "<2>"},
+		{ErrorLevel, "<3>"},
+		{InfoLevel, "<6>"},
+		{DebugLevel, "<7>"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		s := &journaldSink{w: &buf, level: DebugLevel}
+
+		if err := s.Write(Record{Level: tt.level, File: "f.go", Line: 1, Msg: "x"}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		if got := buf.String(); !strings.HasPrefix(got, tt.prefix) {
+			t.Errorf("level %v: line %q does not start with %q", tt.level, got, tt.prefix)
+		}
+	}
+}
+
+func TestJournaldSinkRespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	s := &journaldSink{w: &buf, level: ErrorLevel}
+
+	if err := s.Write(Record{Level: DebugLevel, File: "f.go", Line: 1, Msg: "x"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Debug record written despite level=Error: %q", buf.String())
+	}
+}
@@ -10,16 +10,65 @@
 	log.Default.Level = Debug // Switch to Debug level.
 	log.Default.Time = false // Switch off time information for systemd.
 
+	Debugw, Infow, Errorw and Fatalw log structured records: an event string
+	plus a set of key=value attribute pairs, for greppable and machine
+	parsable logs.
+
+	log.Infow("user logged in", map[string]interface{}{"user": name})
+
+	WithFields returns a child Logger that attaches fields to every
+	structured record it emits.
+
+	A Logger fans every record out to a slice of Sinks, each with its own
+	minimum level. Use AddSink/RemoveSink to log to more than one destination
+	at once, e.g. a rotating file plus syslog plus stderr:
+
+	log.Default.AddSink(log.NewStderrSink())
+	fileSink, _ := log.NewFileSink("app.log", 10<<20, 5)
+	log.Default.AddSink(fileSink)
+	defer log.Default.Close() // closes fileSink; NewStderrSink's Close is a no-op
+
+	SetFormat(FormatJSON) switches stream and file sinks to emit one JSON
+	object per line (ts, level, caller, msg and any structured fields),
+	for ingestion into log aggregators.
+
+	V(level) gives glog-style verbosity gating:
+
+	log.V(2).Infof("retrying, attempt %d", n)
+
+	is a no-op unless Verbosity (or a -logvmodule rule matching the calling
+	file, see SetVModule) is at least 2.
+
+	EveryN, EveryDuration and Once return a Guard that thins out or
+	deduplicates logging from a hot call site:
+
+	log.EveryN(1000).Infof("processed %d so far", count)
+	log.Once("slow-disk").Errorf("disk latency is high")
+
+	SetAsync(bufSize) moves sink I/O off the calling goroutine onto a
+	background worker; Flush blocks until everything buffered so far is
+	written and reports how many records SetDropPolicy's policy has
+	discarded. Fatal records always bypass the buffer.
+
+	RegisterFlags(flag.CommandLine) wires -v, -logvmodule, -logfile,
+	-logtosyslog, -logtime and -alsologtostderr into Default, for drop-in
+	use in server binaries:
+
+	log.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
 	Copyright (C) 2018 Etasoft Inc.
 */
 package log
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -44,28 +93,170 @@ var levelToLetter = map[Level]string{
 	DebugLevel: ".",
 }
 
-// A Logger represents logging type that used writer.
+// A Logger represents logging type that writes to one or more Sinks.
 type Logger struct {
-	// Minimum level to log.
+	// Level is a cheap upper bound checked before a record is even
+	// formatted. Each Sink then applies its own, possibly stricter, level.
 	Level Level
 	Time  bool
+	// Format selects the encoding used by sinks that support more than one
+	// (streamSink, fileSink). Change it with SetFormat.
+	Format Format
+	// Verbosity is the base threshold consulted by V when no -logvmodule rule
+	// matches the calling file. Change it directly or via RegisterFlags.
+	Verbosity int
+
+	fields map[string]interface{}
+	sinks  []Sink
+
+	vmodule    []vmoduleRule
+	vmoduleGen int32
+	vCache     sync.Map
+
+	limiter *rateLimiter
+
+	async      *asyncState
+	dropPolicy DropPolicy
 
-	w io.WriteCloser
 	sync.Mutex
 }
 
-// New logger based on io.WriteCloser.
+// New logger based on io.WriteCloser. The writer is wrapped in a single
+// Sink whose own level is DebugLevel; the Logger's Level starts at
+// ErrorLevel. Use AddSink for finer control or to log to more than one
+// destination.
 func New(w io.WriteCloser) *Logger {
 	return &Logger{
-		w:     w,
-		Level: ErrorLevel,
-		Time:  true,
+		Level:   ErrorLevel,
+		Time:    true,
+		sinks:   []Sink{newStreamSink(w, true)},
+		limiter: newRateLimiter(),
 	}
 }
 
-// Close the writer behind the logger.
+// AddSink attaches a Sink that every subsequent record passing l.Level is
+// fanned out to, in addition to any sinks already configured.
+func (l *Logger) AddSink(s Sink) {
+	l.Lock()
+	l.sinks = append(l.sinks, s)
+	l.Unlock()
+}
+
+// RemoveSink detaches a Sink previously passed to AddSink. It is a no-op if
+// s is not currently attached.
+func (l *Logger) RemoveSink(s Sink) {
+	l.Lock()
+	defer l.Unlock()
+	for i, sink := range l.sinks {
+		if sink == s {
+			l.sinks = append(l.sinks[:i], l.sinks[i+1:]...)
+			return
+		}
+	}
+}
+
+// WithFields returns a child logger that writes to the same destination but
+// attaches the given fields (and any fields inherited from l) to every
+// structured record it emits. The parent's fields are copied rather than
+// shared, so later changes to either logger's fields never race.
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	l.Lock()
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	level, t, format, verbosity, vmodule, limiter, async, dropPolicy, sinks :=
+		l.Level, l.Time, l.Format, l.Verbosity, l.vmodule, l.limiter, l.async, l.dropPolicy, l.sinks
+	l.Unlock()
+
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return &Logger{
+		sinks:      sinks,
+		Level:      level,
+		Time:       t,
+		Format:     format,
+		Verbosity:  verbosity,
+		vmodule:    vmodule,
+		limiter:    limiter,
+		async:      async,
+		dropPolicy: dropPolicy,
+		fields:     merged,
+	}
+}
+
+// formatFields renders fields as a sorted, space-separated list of
+// key=value pairs so the output is greppable and stable across calls.
+func formatFields(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		v := fmt.Sprintf("%v", fields[k])
+		if strings.ContainsAny(v, " \t\"") {
+			v = strconv.Quote(v)
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(v)
+	}
+	return b.String()
+}
+
+// Close stops and drains any async worker, then closes every sink behind
+// the logger.
 func (l *Logger) Close() {
-	l.w.Close()
+	l.Lock()
+	a := l.async
+	l.async = nil
+	sinks := l.sinks
+	l.Unlock()
+
+	if a != nil {
+		l.stopAsync(a)
+	}
+
+	for _, s := range sinks {
+		s.Close()
+	}
+}
+
+// caller computes the file:line of the logging call, skip frames up from
+// the exported Debugf/Infof/... method.
+func caller(skip int) (file string, line int) {
+	_, file, line, ok := runtime.Caller(3 + skip)
+	if !ok {
+		file = "unknown"
+	}
+	return filepath.Base(file), line
+}
+
+// fanOut sends rec to every sink, each one applying its own level and lock.
+// In async mode everything but FatalLevel is buffered instead, so the
+// program doesn't exit before a fatal record is actually written.
+func (l *Logger) fanOut(rec Record) {
+	l.Lock()
+	a := l.async
+	l.Unlock()
+
+	if a != nil && rec.Level != FatalLevel {
+		l.enqueue(a, rec)
+		return
+	}
+
+	l.writeSinks(rec)
 }
 
 // log the message into the logger, at the given level.
@@ -75,39 +266,76 @@ func (l *Logger) log(level Level, skip int, format string, a ...interface{}) {
 		return
 	}
 
-	// Message.
-	msg := fmt.Sprintf(format, a...)
+	l.Lock()
+	outFormat := l.Format
+	l.Unlock()
 
-	// Caller.
-	_, file, line, ok := runtime.Caller(2 + skip)
-	if !ok {
-		file = "unknown"
-	}
-	fl := fmt.Sprintf("%s:%-4d", filepath.Base(file), line)
-	if len(fl) > 18 {
-		fl = fl[len(fl)-18:]
-	}
-	msg = fmt.Sprintf("%-18s", fl) + " " + msg
+	file, line := caller(skip)
+	l.fanOut(Record{
+		Level:    level,
+		Time:     time.Now(),
+		ShowTime: l.Time,
+		Format:   outFormat,
+		File:     file,
+		Line:     line,
+		Msg:      fmt.Sprintf(format, a...),
+	})
+}
 
-	// Level.
-	letter, ok := levelToLetter[level]
-	if !ok {
-		letter = strconv.Itoa(int(level))
-	}
-	msg = letter + " " + msg
+// vlog logs unconditionally, skipping the Level upper bound that log()
+// applies: used by Verbose.Infof, whose caller (V) has already decided the
+// record should be emitted. Sinks still apply their own level.
+func (l *Logger) vlog(level Level, skip int, format string, a ...interface{}) {
+	l.Lock()
+	outFormat := l.Format
+	l.Unlock()
 
-	// Time.
-	if l.Time {
-		msg = time.Now().Format("2006/01/02 15:04:05 ") + msg
-	}
+	file, line := caller(skip)
+	l.fanOut(Record{
+		Level:    level,
+		Time:     time.Now(),
+		ShowTime: l.Time,
+		Format:   outFormat,
+		File:     file,
+		Line:     line,
+		Msg:      fmt.Sprintf(format, a...),
+	})
+}
 
-	if !strings.HasSuffix(msg, "\n") {
-		msg += "\n"
+// logw writes a structured record: the event string and the sorted
+// key=value attribute pairs merged from l.fields and fields.
+func (l *Logger) logw(level Level, skip int, event string, fields map[string]interface{}) {
+	if level > l.Level {
+		return
 	}
 
 	l.Lock()
-	l.w.Write([]byte(msg))
+	parent := l.fields
+	outFormat := l.Format
 	l.Unlock()
+
+	// Always copy into a fresh map, even when parent is empty: fields is the
+	// caller's map, and async mode formats the Record later on the flusher
+	// goroutine, so aliasing it here would race with the caller reusing it.
+	merged := make(map[string]interface{}, len(parent)+len(fields))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	file, line := caller(skip)
+	l.fanOut(Record{
+		Level:    level,
+		Time:     time.Now(),
+		ShowTime: l.Time,
+		Format:   outFormat,
+		File:     file,
+		Line:     line,
+		Event:    event,
+		Fields:   merged,
+	})
 }
 
 // Debugf logs information at a Debug level.
@@ -115,11 +343,23 @@ func (l *Logger) Debugf(format string, a ...interface{}) {
 	l.log(DebugLevel, 1, format, a...)
 }
 
+// Debugw logs a structured record at a Debug level: event is a short,
+// greppable description and fields are rendered as sorted key=value pairs.
+func (l *Logger) Debugw(event string, fields map[string]interface{}) {
+	l.logw(DebugLevel, 1, event, fields)
+}
+
 // Infof logs information at a Info level.
 func (l *Logger) Infof(format string, a ...interface{}) {
 	l.log(InfoLevel, 1, format, a...)
 }
 
+// Infow logs a structured record at an Info level: event is a short,
+// greppable description and fields are rendered as sorted key=value pairs.
+func (l *Logger) Infow(event string, fields map[string]interface{}) {
+	l.logw(InfoLevel, 1, event, fields)
+}
+
 // Errorf logs information at an Error level. It also returns an error
 // constructed with the given message, in case it's useful for the caller.
 func (l *Logger) Errorf(format string, a ...interface{}) error {
@@ -133,6 +373,13 @@ func (l *Logger) Error(err error) error {
 	return err
 }
 
+// Errorw logs a structured record at an Error level and returns an error
+// constructed from event, in case it's useful for the caller.
+func (l *Logger) Errorw(event string, fields map[string]interface{}) error {
+	l.logw(ErrorLevel, 1, event, fields)
+	return errors.New(event)
+}
+
 // Fatalf logs information at a Fatal level, and then exits the program with a
 // non-0 exit code.
 func (l *Logger) Fatalf(format string, a ...interface{}) {
@@ -147,11 +394,19 @@ func (l *Logger) Fatal(err error) {
 	os.Exit(1)
 }
 
+// Fatalw logs a structured record at a Fatal level, and then exits the
+// program with a non-0 exit code.
+func (l *Logger) Fatalw(event string, fields map[string]interface{}) {
+	l.logw(FatalLevel, 1, event, fields)
+	os.Exit(1)
+}
+
 // The default logger, used by the top-level functions below.
 var Default = &Logger{
-	w:     os.Stderr,
-	Level: ErrorLevel,
-	Time:  true,
+	Level:   ErrorLevel,
+	Time:    true,
+	sinks:   []Sink{defaultStderrSink},
+	limiter: newRateLimiter(),
 }
 
 // Debugf is a convenient wrapper to Default.Debugf.
@@ -159,16 +414,31 @@ func Debugf(format string, a ...interface{}) {
 	Default.Debugf(format, a...)
 }
 
+// Debugw is a convenient wrapper to Default.Debugw.
+func Debugw(event string, fields map[string]interface{}) {
+	Default.Debugw(event, fields)
+}
+
 // Infof is a convenient wrapper to Default.Infof.
 func Infof(format string, a ...interface{}) {
 	Default.Infof(format, a...)
 }
 
+// Infow is a convenient wrapper to Default.Infow.
+func Infow(event string, fields map[string]interface{}) {
+	Default.Infow(event, fields)
+}
+
 // Errorf is a convenient wrapper to Default.Errorf.
 func Errorf(format string, a ...interface{}) error {
 	return Default.Errorf(format, a...)
 }
 
+// Errorw is a convenient wrapper to Default.Errorw.
+func Errorw(event string, fields map[string]interface{}) error {
+	return Default.Errorw(event, fields)
+}
+
 func Error(err error) error {
 	return Default.Error(err)
 }
@@ -178,7 +448,17 @@ func Fatalf(format string, a ...interface{}) {
 	Default.Fatalf(format, a...)
 }
 
+// Fatalw is a convenient wrapper to Default.Fatalw.
+func Fatalw(event string, fields map[string]interface{}) {
+	Default.Fatalw(event, fields)
+}
+
 // Fatal is a convenient wrapper to Default.Fatal.
 func Fatal(err error) {
 	Default.Fatal(err)
 }
+
+// WithFields is a convenient wrapper to Default.WithFields.
+func WithFields(fields map[string]interface{}) *Logger {
+	return Default.WithFields(fields)
+}
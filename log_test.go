@@ -0,0 +1,64 @@
+package log
+
+import (
+	"reflect"
+	"testing"
+)
+
+// memSink records every Record it receives, for assertions on what a Logger
+// actually sent downstream without depending on text/JSON rendering.
+type memSink struct {
+	recs []Record
+}
+
+func (s *memSink) Write(rec Record) error {
+	s.recs = append(s.recs, rec)
+	return nil
+}
+
+func (s *memSink) SetLevel(Level) {}
+func (s *memSink) Close() error   { return nil }
+
+func TestWithFieldsCopiesParentFields(t *testing.T) {
+	parent := &Logger{Level: DebugLevel, fields: map[string]interface{}{"service": "api"}}
+
+	child := parent.WithFields(map[string]interface{}{"user": "alice"})
+
+	parent.fields["service"] = "mutated"
+	if child.fields["service"] != "api" {
+		t.Errorf("child.fields[service] = %v, want %q (parent mutation leaked into child)", child.fields["service"], "api")
+	}
+	if child.fields["user"] != "alice" {
+		t.Errorf("child.fields[user] = %v, want %q", child.fields["user"], "alice")
+	}
+}
+
+func TestLogwFieldsNotAliasedToCaller(t *testing.T) {
+	sink := &memSink{}
+	l := &Logger{Level: DebugLevel, sinks: []Sink{sink}, limiter: newRateLimiter()}
+
+	fields := map[string]interface{}{"n": 1}
+	l.Infow("did a thing", fields)
+	fields["n"] = 2 // caller reuses its map after the call returns
+
+	if len(sink.recs) != 1 {
+		t.Fatalf("got %d records, want 1", len(sink.recs))
+	}
+	if got := sink.recs[0].Fields["n"]; got != 1 {
+		t.Errorf("Fields[n] = %v, want 1 (mutating the caller's map after Infow must not be visible)", got)
+	}
+}
+
+func TestLogwCopiesFieldsWhenParentEmpty(t *testing.T) {
+	sink := &memSink{}
+	l := &Logger{Level: DebugLevel, sinks: []Sink{sink}, limiter: newRateLimiter()}
+
+	fields := map[string]interface{}{"n": 1}
+	l.Infow("did a thing", fields)
+
+	got := reflect.ValueOf(sink.recs[0].Fields).Pointer()
+	want := reflect.ValueOf(fields).Pointer()
+	if got == want {
+		t.Fatal("logw must not alias the caller's map even when the parent has no fields")
+	}
+}
@@ -0,0 +1,212 @@
+package log
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	numLimiterShards  = 32
+	limiterTTL        = 10 * time.Minute
+	limiterSweepEvery = time.Minute
+)
+
+// limiterKey identifies one EveryN/EveryDuration/Once guard: the call site
+// (its PC) plus, for Once, the caller-supplied key.
+type limiterKey struct {
+	pc  uintptr
+	key string
+}
+
+type limiterEntry struct {
+	count      uint64
+	last       time.Time
+	seen       bool
+	lastAccess time.Time
+}
+
+type limiterShard struct {
+	mu        sync.Mutex
+	entries   map[limiterKey]*limiterEntry
+	nextSweep time.Time
+}
+
+// rateLimiter tracks EveryN/EveryDuration/Once state in a sharded map with
+// TTL eviction, so logging from a hot loop with many distinct keys (e.g. one
+// Once per request ID) doesn't grow memory without bound.
+type rateLimiter struct {
+	shards [numLimiterShards]*limiterShard
+}
+
+func newRateLimiter() *rateLimiter {
+	rl := &rateLimiter{}
+	for i := range rl.shards {
+		rl.shards[i] = &limiterShard{entries: make(map[limiterKey]*limiterEntry)}
+	}
+	return rl
+}
+
+func (rl *rateLimiter) shardFor(k limiterKey) *limiterShard {
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(k.pc))
+	h.Write(buf[:])
+	h.Write([]byte(k.key))
+	return rl.shards[h.Sum64()%numLimiterShards]
+}
+
+// sweep removes entries untouched for longer than limiterTTL. Caller must
+// hold s.mu. Runs at most once per limiterSweepEvery per shard.
+func (s *limiterShard) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(limiterSweepEvery)
+	for k, e := range s.entries {
+		if now.Sub(e.lastAccess) > limiterTTL {
+			delete(s.entries, k)
+		}
+	}
+}
+
+// allowEveryN reports whether this is the 1st, (n+1)th, (2n+1)th, ... call
+// for k. n <= 0 always allows.
+func (rl *rateLimiter) allowEveryN(k limiterKey, n int) bool {
+	if n <= 0 {
+		return true
+	}
+
+	s := rl.shardFor(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	e := s.entries[k]
+	if e == nil {
+		e = &limiterEntry{}
+		s.entries[k] = e
+	}
+	e.count++
+	e.lastAccess = now
+	return (e.count-1)%uint64(n) == 0
+}
+
+// allowEveryDuration reports whether at least d has passed since the last
+// allowed call for k.
+func (rl *rateLimiter) allowEveryDuration(k limiterKey, d time.Duration) bool {
+	s := rl.shardFor(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	e := s.entries[k]
+	if e != nil && now.Sub(e.last) < d {
+		e.lastAccess = now
+		return false
+	}
+
+	s.entries[k] = &limiterEntry{last: now, lastAccess: now}
+	return true
+}
+
+// allowOnce reports whether this is the first call for k, i.e. whether k
+// has not been seen before.
+func (rl *rateLimiter) allowOnce(k limiterKey) bool {
+	s := rl.shardFor(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	e := s.entries[k]
+	if e != nil && e.seen {
+		e.lastAccess = now
+		return false
+	}
+
+	s.entries[k] = &limiterEntry{seen: true, lastAccess: now}
+	return true
+}
+
+// Guard gates a burst of would-be log calls down to the ones allowed by the
+// EveryN/EveryDuration/Once check that produced it.
+type Guard struct {
+	l  *Logger
+	ok bool
+}
+
+// Debugf logs at Debug level if the guard allows it.
+func (g *Guard) Debugf(format string, a ...interface{}) {
+	if !g.ok {
+		return
+	}
+	g.l.log(DebugLevel, 0, format, a...)
+}
+
+// Infof logs at Info level if the guard allows it.
+func (g *Guard) Infof(format string, a ...interface{}) {
+	if !g.ok {
+		return
+	}
+	g.l.log(InfoLevel, 0, format, a...)
+}
+
+// Errorf logs at Error level if the guard allows it, returning an error
+// constructed with the given message either way.
+func (g *Guard) Errorf(format string, a ...interface{}) error {
+	if g.ok {
+		g.l.log(ErrorLevel, 0, format, a...)
+	}
+	return fmt.Errorf(format, a...)
+}
+
+// EveryN returns a Guard that allows the 1st, (n+1)th, (2n+1)th, ... call
+// made from this call site. Use it to thin out logging in a hot loop:
+//
+//	log.EveryN(1000).Infof("processed %d so far", count)
+func (l *Logger) EveryN(n int) *Guard {
+	pc, _, _, _ := runtime.Caller(1)
+	return &Guard{l: l, ok: l.limiter.allowEveryN(limiterKey{pc: pc}, n)}
+}
+
+// EveryN is a convenient wrapper to Default.EveryN.
+func EveryN(n int) *Guard {
+	pc, _, _, _ := runtime.Caller(1)
+	return &Guard{l: Default, ok: Default.limiter.allowEveryN(limiterKey{pc: pc}, n)}
+}
+
+// EveryDuration returns a Guard that allows a call made from this call site
+// at most once every d.
+func (l *Logger) EveryDuration(d time.Duration) *Guard {
+	pc, _, _, _ := runtime.Caller(1)
+	return &Guard{l: l, ok: l.limiter.allowEveryDuration(limiterKey{pc: pc}, d)}
+}
+
+// EveryDuration is a convenient wrapper to Default.EveryDuration.
+func EveryDuration(d time.Duration) *Guard {
+	pc, _, _, _ := runtime.Caller(1)
+	return &Guard{l: Default, ok: Default.limiter.allowEveryDuration(limiterKey{pc: pc}, d)}
+}
+
+// Once returns a Guard that allows only the first call made for key from
+// this call site, suppressing repeats. A key left without a call for
+// longer than limiterTTL is forgotten, so its next call is allowed again.
+func (l *Logger) Once(key string) *Guard {
+	pc, _, _, _ := runtime.Caller(1)
+	return &Guard{l: l, ok: l.limiter.allowOnce(limiterKey{pc: pc, key: key})}
+}
+
+// Once is a convenient wrapper to Default.Once.
+func Once(key string) *Guard {
+	pc, _, _, _ := runtime.Caller(1)
+	return &Guard{l: Default, ok: Default.limiter.allowOnce(limiterKey{pc: pc, key: key})}
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestAllowEveryN(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want []bool
+	}{
+		{"n=1 allows every call", 1, []bool{true, true, true, true}},
+		{"n=3 allows 1st, 4th, 7th", 3, []bool{true, false, false, true, false, false, true}},
+		{"n<=0 always allows", 0, []bool{true, true, true}},
+		{"n negative always allows", -5, []bool{true, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl := newRateLimiter()
+			k := limiterKey{pc: 1}
+			for i, want := range tt.want {
+				if got := rl.allowEveryN(k, tt.n); got != want {
+					t.Errorf("call %d: allowEveryN(%d) = %v, want %v", i+1, tt.n, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAllowEveryNDistinctKeysIndependent(t *testing.T) {
+	rl := newRateLimiter()
+	a := limiterKey{pc: 1}
+	b := limiterKey{pc: 2}
+
+	if !rl.allowEveryN(a, 2) {
+		t.Fatal("first call for key a should be allowed")
+	}
+	if !rl.allowEveryN(b, 2) {
+		t.Fatal("first call for key b should be allowed (independent of a)")
+	}
+	if rl.allowEveryN(a, 2) {
+		t.Fatal("second call for key a should be suppressed")
+	}
+}
+
+func TestGuardInfofRecordsCallSite(t *testing.T) {
+	sink := &memSink{}
+	l := &Logger{Level: DebugLevel, sinks: []Sink{sink}, limiter: newRateLimiter()}
+
+	_, _, wantLine, _ := runtime.Caller(0)
+	wantLine += 2 // the EveryN(1).Infof call is two lines below
+	l.EveryN(1).Infof("hello")
+
+	if len(sink.recs) != 1 {
+		t.Fatalf("got %d records, want 1", len(sink.recs))
+	}
+	if got := sink.recs[0].File; got != "ratelimit_test.go" {
+		t.Errorf("File = %q, want %q (Guard.Infof must report its own call site, not log()'s)", got, "ratelimit_test.go")
+	}
+	if got := sink.recs[0].Line; got != wantLine {
+		t.Errorf("Line = %d, want %d", got, wantLine)
+	}
+}
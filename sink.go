@@ -0,0 +1,145 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Record is the data passed to a Sink for every logged line, before any
+// sink-specific formatting is applied.
+type Record struct {
+	Level Level
+	Time  time.Time
+	// ShowTime reflects the owning Logger's Time flag at the moment the
+	// record was produced. Sinks that always omit or always include a
+	// timestamp (journald, syslog) are free to ignore it.
+	ShowTime bool
+	// Format reflects the owning Logger's Format at the moment the record
+	// was produced. Sinks with a single wire format (journald, syslog) are
+	// free to ignore it.
+	Format Format
+
+	File string
+	Line int
+
+	// Msg is set for printf-style records (Debugf, Infof, ...).
+	Msg string
+	// Event and Fields are set for structured records (Debugw, Infow, ...).
+	Event  string
+	Fields map[string]interface{}
+}
+
+// text renders the classic "file:line msg" body shared by the printf-style
+// and structured logging paths, without time or level.
+func (r Record) text() string {
+	fl := fmt.Sprintf("%s:%-4d", r.File, r.Line)
+	if len(fl) > 18 {
+		fl = fl[len(fl)-18:]
+	}
+	body := fmt.Sprintf("%-18s", fl) + " "
+
+	if r.Fields != nil || r.Event != "" {
+		body += r.Event
+		if kv := formatFields(r.Fields); kv != "" {
+			body += " " + kv
+		}
+		return body
+	}
+	return body + r.Msg
+}
+
+// letter returns the single-character level marker, e.g. "E" for ErrorLevel.
+func (r Record) letter() string {
+	if l, ok := levelToLetter[r.Level]; ok {
+		return l
+	}
+	return strconv.Itoa(int(r.Level))
+}
+
+// Sink is a logging destination with its own minimum level. Logger.log and
+// Logger.logw fan every Record out to all of a Logger's sinks.
+type Sink interface {
+	// Write emits rec if rec.Level is enabled for this sink. Implementations
+	// must be safe for concurrent use.
+	Write(rec Record) error
+	// SetLevel changes the sink's own minimum level.
+	SetLevel(level Level)
+	// Close releases any resources held by the sink.
+	Close() error
+}
+
+// streamSink writes text- or JSON-formatted records (per Record.Format) to
+// an io.WriteCloser, guarded by
+// its own lock. It backs the io.WriteCloser passed to New.
+type streamSink struct {
+	mu    sync.Mutex
+	w     io.WriteCloser
+	level Level
+	time  bool
+	// owned is false for sinks wrapping a writer the sink doesn't own, such
+	// as os.Stderr, so Close doesn't close a process-wide fd out from under
+	// every other stderr writer.
+	owned bool
+}
+
+// newStreamSink wraps w in a Sink that logs everything up to DebugLevel,
+// rendering a timestamp when withTime is true. Close closes w.
+func newStreamSink(w io.WriteCloser, withTime bool) *streamSink {
+	return &streamSink{w: w, level: DebugLevel, time: withTime, owned: true}
+}
+
+// newStderrStreamSink wraps os.Stderr in a Sink, like newStreamSink, but
+// with Close left a no-op since the sink doesn't own the process's stderr.
+func newStderrStreamSink() *streamSink {
+	return &streamSink{w: os.Stderr, level: DebugLevel, time: true}
+}
+
+// NewStderrSink returns a Sink writing text- or JSON-formatted records (per
+// Record.Format) to os.Stderr,
+// preserving the package's original behavior. Close on the returned Sink is
+// a no-op: the sink doesn't own os.Stderr and must not close it.
+func NewStderrSink() Sink {
+	return newStderrStreamSink()
+}
+
+func (s *streamSink) Write(rec Record) error {
+	if rec.Level > s.level {
+		return nil
+	}
+
+	var b []byte
+	if rec.Format == FormatJSON {
+		b = rec.json()
+	} else {
+		line := rec.letter() + " " + rec.text()
+		if s.time && rec.ShowTime {
+			line = rec.Time.Format("2006/01/02 15:04:05 ") + line
+		}
+		if line == "" || line[len(line)-1] != '\n' {
+			line += "\n"
+		}
+		b = []byte(line)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(b)
+	return err
+}
+
+func (s *streamSink) SetLevel(level Level) {
+	s.mu.Lock()
+	s.level = level
+	s.mu.Unlock()
+}
+
+func (s *streamSink) Close() error {
+	if !s.owned {
+		return nil
+	}
+	return s.w.Close()
+}
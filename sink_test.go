@@ -0,0 +1,57 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// closeTrackingWriter is a bytes.Buffer-backed io.WriteCloser that records
+// whether Close was called, so tests can assert on Close behavior directly
+// instead of on a process-global like os.Stdout/os.Stderr.
+type closeTrackingWriter struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (w *closeTrackingWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+// TestLoggerCloseClosesOwnedSinkOnly guards against NewStderrSink's Close
+// closing the process's shared os.Stderr: a Logger.Close should close the
+// writer behind New (which it owns) but leave a stderr-backed sink's writer
+// untouched (which it doesn't).
+func TestLoggerCloseClosesOwnedSinkOnly(t *testing.T) {
+	owned := &closeTrackingWriter{}
+	l := New(owned)
+	l.AddSink(NewStderrSink())
+
+	l.Close()
+
+	if !owned.closed {
+		t.Error("Close did not close the writer owned by New")
+	}
+	if _, err := os.Stderr.Stat(); err != nil {
+		t.Errorf("os.Stderr unusable after Close, the stderr sink closed it: %v", err)
+	}
+}
+
+func TestStderrSinkCloseIsNoop(t *testing.T) {
+	if err := NewStderrSink().Close(); err != nil {
+		t.Fatalf("NewStderrSink().Close(): %v", err)
+	}
+}
+
+func TestStreamSinkCloseLeavesUnownedWriterOpen(t *testing.T) {
+	w := &closeTrackingWriter{} // owned defaults to false
+	s := &streamSink{w: w, level: DebugLevel, time: true}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if w.closed {
+		t.Error("unowned streamSink's Close closed the underlying writer")
+	}
+}
@@ -0,0 +1,72 @@
+//go:build !windows && !nacl && !plan9
+// +build !windows,!nacl,!plan9
+
+package log
+
+import (
+	"log/syslog"
+	"sync"
+)
+
+// syslogWriter is the subset of *syslog.Writer's behavior syslogSink needs.
+// Tests substitute a fake implementation to exercise the level-to-severity
+// mapping without a local syslog daemon.
+type syslogWriter interface {
+	Crit(m string) error
+	Err(m string) error
+	Info(m string) error
+	Debug(m string) error
+	Close() error
+}
+
+// syslogSink writes records to the local syslog daemon. The timestamp and
+// host/tag prefix are added by syslog itself, so only the file:line and
+// message body are written.
+type syslogSink struct {
+	mu    sync.Mutex
+	w     syslogWriter
+	level Level
+}
+
+// NewSyslogSink dials the local syslog daemon tagged with tag and logging
+// at facility, and returns a Sink that maps each Level to the matching
+// syslog severity (Crit, Err, Info, Debug).
+func NewSyslogSink(tag string, facility syslog.Priority) (Sink, error) {
+	w, err := syslog.New(facility|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{w: w, level: DebugLevel}, nil
+}
+
+func (s *syslogSink) Write(rec Record) error {
+	if rec.Level > s.level {
+		return nil
+	}
+
+	msg := rec.text()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch rec.Level {
+	case FatalLevel:
+		return s.w.Crit(msg)
+	case ErrorLevel:
+		return s.w.Err(msg)
+	case InfoLevel:
+		return s.w.Info(msg)
+	default:
+		return s.w.Debug(msg)
+	}
+}
+
+func (s *syslogSink) SetLevel(level Level) {
+	s.mu.Lock()
+	s.level = level
+	s.mu.Unlock()
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
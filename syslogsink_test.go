@@ -0,0 +1,58 @@
+//go:build !windows && !nacl && !plan9
+// +build !windows,!nacl,!plan9
+
+package log
+
+import "testing"
+
+// fakeSyslogWriter records which severity method syslogSink invoked, so
+// tests can check the Level-to-severity mapping without a local syslog
+// daemon.
+type fakeSyslogWriter struct {
+	method, msg string
+}
+
+func (f *fakeSyslogWriter) Crit(m string) error  { f.method, f.msg = "crit", m; return nil }
+func (f *fakeSyslogWriter) Err(m string) error   { f.method, f.msg = "err", m; return nil }
+func (f *fakeSyslogWriter) Info(m string) error  { f.method, f.msg = "info", m; return nil }
+func (f *fakeSyslogWriter) Debug(m string) error { f.method, f.msg = "debug", m; return nil }
+func (f *fakeSyslogWriter) Close() error         { return nil }
+
+func TestSyslogSinkSeverityMapping(t *testing.T) {
+	tests := []struct {
+		level  Level
+		method string
+	}{
+		{FatalLevel, "crit"},
+		{ErrorLevel, "err"},
+		{InfoLevel, "info"},
+		{DebugLevel, "debug"},
+	}
+
+	for _, tt := range tests {
+		w := &fakeSyslogWriter{}
+		s := &syslogSink{w: w, level: DebugLevel}
+
+		if err := s.Write(Record{Level: tt.level, File: "f.go", Line: 1, Msg: "boom"}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		if w.method != tt.method {
+			t.Errorf("level %v: called %q, want %q", tt.level, w.method, tt.method)
+		}
+		if w.msg == "" || w.msg[len(w.msg)-1] == '\n' {
+			t.Errorf("level %v: msg %q should be the bare text() body, no trailing newline", tt.level, w.msg)
+		}
+	}
+}
+
+func TestSyslogSinkRespectsLevel(t *testing.T) {
+	w := &fakeSyslogWriter{}
+	s := &syslogSink{w: w, level: ErrorLevel}
+
+	if err := s.Write(Record{Level: DebugLevel, File: "f.go", Line: 1, Msg: "x"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if w.method != "" {
+		t.Errorf("Debug record dispatched to %q despite level=Error", w.method)
+	}
+}
@@ -0,0 +1,138 @@
+package log
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Verbose is returned by Logger.V. Its Infof is a no-op unless the verbosity
+// configured for the calling file is at least as high as the V level.
+type Verbose struct {
+	enabled bool
+	l       *Logger
+}
+
+// Infof logs at Info level if v is enabled. It bypasses the Logger.Level
+// upper bound: V already decided this call site should be emitted, and
+// requiring Level >= Info too would force every plain Infof/Debugf to log
+// globally just to see one verbose subsystem, defeating the point of
+// -logvmodule.
+func (v Verbose) Infof(format string, a ...interface{}) {
+	if !v.enabled {
+		return
+	}
+	v.l.vlog(InfoLevel, 0, format, a...)
+}
+
+// vmoduleRule is one "pattern=level" rule from a -logvmodule spec. pattern is
+// matched against the caller's file basename with filepath.Match, so "*"
+// and "?" work as usual.
+type vmoduleRule struct {
+	pattern string
+	level   int
+}
+
+// vCacheEntry is the verbosity resolved for one call site (keyed by PC),
+// valid as long as gen matches the Logger's current vmoduleGen and base
+// matches the Logger's current Verbosity. base is included because
+// Verbosity can be changed directly (see the Logger.Verbosity doc comment)
+// without bumping vmoduleGen.
+type vCacheEntry struct {
+	gen   int32
+	base  int
+	level int
+}
+
+// V reports whether verbosity level is enabled for the calling file, so
+//
+//	log.V(2).Infof("starting retry %d", n)
+//
+// is a no-op unless the configured verbosity (or a matching -logvmodule rule)
+// is at least 2.
+func (l *Logger) V(level int) Verbose {
+	pc, _, _, _ := runtime.Caller(1)
+	return l.vForPC(level, pc)
+}
+
+// V is a convenient wrapper to Default.V.
+func V(level int) Verbose {
+	pc, _, _, _ := runtime.Caller(1)
+	return Default.vForPC(level, pc)
+}
+
+func (l *Logger) vForPC(level int, pc uintptr) Verbose {
+	return Verbose{enabled: level <= l.effectiveVerbosity(pc), l: l}
+}
+
+// effectiveVerbosity resolves the verbosity threshold for the call site at
+// pc, consulting -logvmodule rules and caching the decision per PC until the
+// rules next change.
+func (l *Logger) effectiveVerbosity(pc uintptr) int {
+	l.Lock()
+	gen := l.vmoduleGen
+	rules := l.vmodule
+	base := l.Verbosity
+	l.Unlock()
+
+	if cached, ok := l.vCache.Load(pc); ok {
+		if entry := cached.(vCacheEntry); entry.gen == gen && entry.base == base {
+			return entry.level
+		}
+	}
+
+	level := base
+	if len(rules) > 0 && pc != 0 {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			file, _ := fn.FileLine(pc)
+			name := filepath.Base(file)
+			for _, r := range rules {
+				if ok, _ := filepath.Match(r.pattern, name); ok {
+					level = r.level
+					break
+				}
+			}
+		}
+	}
+
+	l.vCache.Store(pc, vCacheEntry{gen: gen, base: base, level: level})
+	return level
+}
+
+// SetVModule configures per-file verbosity overrides from a comma-separated
+// list of "pattern=level" rules, e.g. "worker*.go=2,*_test.go=3". pattern is
+// matched against the logging call's file basename.
+func (l *Logger) SetVModule(spec string) error {
+	var rules []vmoduleRule
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("log: invalid -logvmodule rule %q", part)
+		}
+		level, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return fmt.Errorf("log: invalid -logvmodule level in %q: %v", part, err)
+		}
+		if _, err := filepath.Match(kv[0], ""); err != nil {
+			return fmt.Errorf("log: invalid -logvmodule pattern %q: %v", part, err)
+		}
+		rules = append(rules, vmoduleRule{pattern: kv[0], level: level})
+	}
+
+	l.Lock()
+	l.vmodule = rules
+	l.vmoduleGen++
+	l.Unlock()
+	return nil
+}
+
+// SetVModule is a convenient wrapper to Default.SetVModule.
+func SetVModule(spec string) error {
+	return Default.SetVModule(spec)
+}
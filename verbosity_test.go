@@ -0,0 +1,97 @@
+package log
+
+import (
+	"runtime"
+	"testing"
+)
+
+// callerPC returns a real PC so effectiveVerbosity can resolve it with
+// runtime.FuncForPC, the way V's actual callers do.
+func callerPC() uintptr {
+	pc, _, _, _ := runtime.Caller(0)
+	return pc
+}
+
+func TestEffectiveVerbosityUsesBaseWithoutRules(t *testing.T) {
+	l := &Logger{Verbosity: 2}
+
+	if got := l.effectiveVerbosity(0); got != 2 {
+		t.Errorf("effectiveVerbosity = %d, want 2", got)
+	}
+}
+
+func TestEffectiveVerbosityCachePerPC(t *testing.T) {
+	l := &Logger{Verbosity: 1}
+	pc := callerPC()
+
+	if got := l.effectiveVerbosity(pc); got != 1 {
+		t.Fatalf("effectiveVerbosity = %d, want 1", got)
+	}
+
+	// Mutate vCache directly to a sentinel value: if the second call doesn't
+	// hit the cache (gen and base both still match), it would recompute 1,
+	// not observe this sentinel.
+	l.vCache.Store(pc, vCacheEntry{gen: l.vmoduleGen, base: l.Verbosity, level: 99})
+	if got := l.effectiveVerbosity(pc); got != 99 {
+		t.Errorf("effectiveVerbosity = %d, want 99 (cached entry not reused)", got)
+	}
+}
+
+func TestEffectiveVerbosityInvalidatedByVerbosityChange(t *testing.T) {
+	l := &Logger{Verbosity: 1}
+	pc := callerPC()
+
+	if got := l.effectiveVerbosity(pc); got != 1 {
+		t.Fatalf("effectiveVerbosity = %d, want 1", got)
+	}
+
+	l.Verbosity = 3
+	if got := l.effectiveVerbosity(pc); got != 3 {
+		t.Errorf("effectiveVerbosity = %d, want 3 after Verbosity changed (stale cache entry reused)", got)
+	}
+}
+
+func TestEffectiveVerbosityInvalidatedBySetVModule(t *testing.T) {
+	l := &Logger{Verbosity: 0}
+	pc := callerPC()
+
+	if got := l.effectiveVerbosity(pc); got != 0 {
+		t.Fatalf("effectiveVerbosity = %d, want 0", got)
+	}
+
+	if err := l.SetVModule("*=5"); err != nil {
+		t.Fatalf("SetVModule: %v", err)
+	}
+	if got := l.effectiveVerbosity(pc); got != 5 {
+		t.Errorf("effectiveVerbosity = %d, want 5 after SetVModule bumped vmoduleGen (stale cache entry reused)", got)
+	}
+}
+
+func TestVInfofRecordsCallSite(t *testing.T) {
+	sink := &memSink{}
+	l := &Logger{Level: DebugLevel, Verbosity: 2, sinks: []Sink{sink}, limiter: newRateLimiter()}
+
+	_, _, wantLine, _ := runtime.Caller(0)
+	wantLine += 2 // the V(2).Infof call is two lines below
+	l.V(2).Infof("hello")
+
+	if len(sink.recs) != 1 {
+		t.Fatalf("got %d records, want 1", len(sink.recs))
+	}
+	if got := sink.recs[0].File; got != "verbosity_test.go" {
+		t.Errorf("File = %q, want %q (V(n).Infof must report its own call site, not vlog's)", got, "verbosity_test.go")
+	}
+	if got := sink.recs[0].Line; got != wantLine {
+		t.Errorf("Line = %d, want %d", got, wantLine)
+	}
+}
+
+func TestSetVModuleRejectsMalformedRule(t *testing.T) {
+	l := &Logger{}
+	if err := l.SetVModule("notanumber"); err == nil {
+		t.Error("SetVModule(\"notanumber\") should return an error")
+	}
+	if err := l.SetVModule("pattern=notanumber"); err == nil {
+		t.Error("SetVModule(\"pattern=notanumber\") should return an error")
+	}
+}